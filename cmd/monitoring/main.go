@@ -51,17 +51,18 @@ func main() {
 	client := rpc.New(cfg.Solana.RPCEndpoint)
 
 	schemaRegistry := monitoring.NewSchemaRegistry(cfg.SchemaRegistry, log)
-	trSchema, err := schemaRegistry.EnsureSchema("transmission-value", monitoring.TransmissionAvroSchema)
+	subjectStrategy := cfg.SchemaRegistry.SubjectNameStrategy
+	trSchema, err := schemaRegistry.EnsureSchema(subjectStrategy.SubjectFor("transmission", "transmission"), monitoring.TransmissionAvroSchema)
 
 	if err != nil {
 		log.Fatalw("failed to prepare transmission schema", "error", err)
 	}
-	stSchema, err := schemaRegistry.EnsureSchema(cfg.Kafka.ConfigSetTopic+"-value", monitoring.ConfigSetAvroSchema)
+	stSchema, err := schemaRegistry.EnsureSchema(subjectStrategy.SubjectFor(cfg.Kafka.ConfigSetTopic, "config_set"), monitoring.ConfigSetAvroSchema)
 	if err != nil {
 		log.Fatalf("failed to prepare config_set schema", "error", err)
 	}
 
-	csSimplifiedSchema, err := schemaRegistry.EnsureSchema(cfg.Kafka.ConfigSetSimplifiedTopic+"-value", monitoring.ConfigSetSimplifiedAvroSchema)
+	csSimplifiedSchema, err := schemaRegistry.EnsureSchema(subjectStrategy.SubjectFor(cfg.Kafka.ConfigSetSimplifiedTopic, "config_set_simplified"), monitoring.ConfigSetSimplifiedAvroSchema)
 	if err != nil {
 		log.Fatalf("failed to prepare config_set_simplified schema", "error", err)
 	}
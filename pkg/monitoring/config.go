@@ -0,0 +1,79 @@
+package monitoring
+
+import "time"
+
+// SchemaRegistryConfig holds the settings needed to talk to a Confluent
+// Schema Registry instance.
+type SchemaRegistryConfig struct {
+	URL      string
+	Username string
+	Password string
+
+	// CacheTTL controls how long a schema fetched from the registry is
+	// considered fresh before EnsureSchema/GetSchemaByID will re-fetch it.
+	// A zero value disables expiry: entries stay cached until evicted by
+	// the cache's size cap or cleared with Flush.
+	CacheTTL time.Duration
+
+	// Compatibility is the compatibility level applied to a subject each
+	// time EnsureSchema writes a new schema version for it, whether by
+	// creating the subject or by updating it. An empty value leaves the
+	// registry's own default in place.
+	Compatibility CompatibilityLevel
+
+	// Format selects the serialization EnsureSchema registers and encodes
+	// with. An empty value defaults to avro, matching existing behaviour.
+	Format Format
+
+	// SubjectNameStrategy controls how a topic and record name are
+	// combined into a subject name. An empty value defaults to
+	// TopicNameStrategy, matching existing behaviour.
+	SubjectNameStrategy SubjectNameStrategy
+}
+
+// SubjectNameStrategy selects one of the three Confluent-standard ways of
+// deriving a Schema Registry subject name for a message.
+type SubjectNameStrategy string
+
+const (
+	// TopicNameStrategy names the subject "<topic>-value". This is
+	// Confluent's default and assumes a topic only ever carries one
+	// record type.
+	TopicNameStrategy SubjectNameStrategy = "topic"
+	// RecordNameStrategy names the subject after the record type alone,
+	// independent of topic, so the same record can be published to
+	// several topics under one subject.
+	RecordNameStrategy SubjectNameStrategy = "record"
+	// TopicRecordNameStrategy combines both, so a single topic can carry
+	// several distinct record types, each under its own subject.
+	TopicRecordNameStrategy SubjectNameStrategy = "topic-record"
+)
+
+// SubjectFor derives the Schema Registry subject name for a message
+// published to topic carrying records named recordName, according to
+// strategy. The zero value of SubjectNameStrategy behaves as
+// TopicNameStrategy.
+func (strategy SubjectNameStrategy) SubjectFor(topic, recordName string) string {
+	switch strategy {
+	case RecordNameStrategy:
+		return recordName + "-value"
+	case TopicRecordNameStrategy:
+		return topic + "-" + recordName + "-value"
+	default:
+		return topic + "-value"
+	}
+}
+
+// CompatibilityLevel is one of the compatibility levels Confluent's Schema
+// Registry supports for a subject.
+type CompatibilityLevel string
+
+const (
+	CompatibilityNone               CompatibilityLevel = "NONE"
+	CompatibilityBackward           CompatibilityLevel = "BACKWARD"
+	CompatibilityBackwardTransitive CompatibilityLevel = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            CompatibilityLevel = "FORWARD"
+	CompatibilityForwardTransitive  CompatibilityLevel = "FORWARD_TRANSITIVE"
+	CompatibilityFull               CompatibilityLevel = "FULL"
+	CompatibilityFullTransitive     CompatibilityLevel = "FULL_TRANSITIVE"
+)
@@ -0,0 +1,28 @@
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectNameStrategy_SubjectFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   SubjectNameStrategy
+		topic      string
+		recordName string
+		want       string
+	}{
+		{"topic strategy names the subject after the topic", TopicNameStrategy, "transmission", "transmission_value", "transmission-value"},
+		{"record strategy names the subject after the record", RecordNameStrategy, "feed-a", "transmission_value", "transmission_value-value"},
+		{"record strategy ignores which topic it's on", RecordNameStrategy, "feed-b", "transmission_value", "transmission_value-value"},
+		{"topic-record strategy combines both", TopicRecordNameStrategy, "feed-a", "transmission_value", "feed-a-transmission_value-value"},
+		{"zero value defaults to topic strategy", SubjectNameStrategy(""), "config_set", "config_set", "config_set-value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.strategy.SubjectFor(tt.topic, tt.recordName))
+		})
+	}
+}
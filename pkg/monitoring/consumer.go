@@ -0,0 +1,79 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/smartcontractkit/chainlink/core/logger"
+)
+
+// readPollTimeout bounds each call to ReadMessage so Start's loop wakes up
+// often enough to notice ctx being canceled, even on an idle topic.
+const readPollTimeout = 100 * time.Millisecond
+
+// Consumer reads messages off one or more Kafka topics and decodes them
+// with a Decoder. It's the read-side counterpart to Producer: operators can
+// use it to run integration tests, tail topics for debugging, or reprocess
+// historical transmission/config_set messages without a separate tool.
+type Consumer interface {
+	// Start blocks reading and decoding messages, invoking onMessage for
+	// each one that decodes, until ctx is canceled. Messages that fail to
+	// read or decode are logged and skipped.
+	Start(ctx context.Context, onMessage func(topic string, value interface{})) error
+	Close()
+}
+
+type consumer struct {
+	backend *kafka.Consumer
+	decoder Decoder
+	log     logger.Logger
+}
+
+// NewConsumer creates a Consumer subscribed to topics, using groupID as the
+// Kafka consumer group, and decoding every message with decoder.
+func NewConsumer(cfg KafkaConfig, groupID string, topics []string, decoder Decoder, log logger.Logger) (Consumer, error) {
+	backend, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.Brokers,
+		"group.id":          groupID,
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+	if err := backend.SubscribeTopics(topics, nil); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to topics '%v': %w", topics, err)
+	}
+	return &consumer{backend, decoder, log}, nil
+}
+
+func (c *consumer) Start(ctx context.Context, onMessage func(topic string, value interface{})) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		msg, err := c.backend.ReadMessage(readPollTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrTimedOut {
+				continue
+			}
+			c.log.Errorw("failed to read message from kafka", "error", err)
+			continue
+		}
+		value, err := c.decoder.Decode(msg.Value)
+		if err != nil {
+			c.log.Errorw("failed to decode message", "topic", *msg.TopicPartition.Topic, "error", err)
+			continue
+		}
+		onMessage(*msg.TopicPartition.Topic, value)
+	}
+}
+
+func (c *consumer) Close() {
+	c.backend.Close()
+}
@@ -0,0 +1,62 @@
+package monitoring
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// wireFormatMagicByte is the leading byte Confluent's Schema Registry wire
+// format prepends to every encoded message, ahead of the 4-byte schema ID.
+const wireFormatMagicByte = 0
+
+// wireFormatHeaderLen is the length in bytes of the magic byte plus the
+// big-endian schema ID that precedes the encoded payload.
+const wireFormatHeaderLen = 5
+
+// Decoder turns a raw Kafka message payload, written in the Schema Registry
+// wire format, back into a Go value. Unlike Schema.Encode - which always
+// writes with the schema it was constructed from - Decode must resolve
+// whichever schema ID the message was actually written with, since a topic
+// can contain records spanning several schema versions.
+type Decoder interface {
+	Decode(raw []byte) (interface{}, error)
+}
+
+type decoder struct {
+	registry SchemaRegistry
+}
+
+// NewDecoder builds a Decoder that resolves schemas through registry by ID
+// as they're encountered on the wire, rather than assuming a fixed schema.
+func NewDecoder(registry SchemaRegistry) Decoder {
+	return &decoder{registry}
+}
+
+func (d *decoder) Decode(raw []byte) (interface{}, error) {
+	schemaID, payload, err := parseWireFormatHeader(raw)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := d.registry.GetSchemaByID(schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schema with id '%d': %w", schemaID, err)
+	}
+	value, err := schema.Decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload encoded with schema id '%d': %w", schemaID, err)
+	}
+	return value, nil
+}
+
+// parseWireFormatHeader splits a raw message into the schema ID its wire
+// format header declares and the payload that follows it.
+func parseWireFormatHeader(raw []byte) (uint32, []byte, error) {
+	if len(raw) < wireFormatHeaderLen {
+		return 0, nil, fmt.Errorf("message is too short to contain a wire-format header: got %d bytes", len(raw))
+	}
+	if raw[0] != wireFormatMagicByte {
+		return 0, nil, fmt.Errorf("unexpected magic byte '%d', expected '%d'", raw[0], wireFormatMagicByte)
+	}
+	schemaID := binary.BigEndian.Uint32(raw[1:wireFormatHeaderLen])
+	return schemaID, raw[wireFormatHeaderLen:], nil
+}
@@ -0,0 +1,93 @@
+package monitoring
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWireFormatHeader(t *testing.T) {
+	t.Run("splits schema id from payload", func(t *testing.T) {
+		raw := []byte{0, 0, 0, 0, 42, 'p', 'a', 'y', 'l', 'o', 'a', 'd'}
+		schemaID, payload, err := parseWireFormatHeader(raw)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(42), schemaID)
+		assert.Equal(t, []byte("payload"), payload)
+	})
+
+	t.Run("rejects messages shorter than the header", func(t *testing.T) {
+		_, _, err := parseWireFormatHeader([]byte{0, 0, 0, 0})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects an unexpected magic byte", func(t *testing.T) {
+		_, _, err := parseWireFormatHeader([]byte{1, 0, 0, 0, 42, 'x'})
+		assert.Error(t, err)
+	})
+}
+
+type fakeSchemaRegistry struct {
+	SchemaRegistry
+	schemas map[uint32]Schema
+	err     error
+}
+
+func (f *fakeSchemaRegistry) GetSchemaByID(id uint32) (Schema, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	schema, ok := f.schemas[id]
+	if !ok {
+		return nil, fmt.Errorf("no schema registered for id '%d'", id)
+	}
+	return schema, nil
+}
+
+type fakeSchema struct {
+	decoded interface{}
+	err     error
+}
+
+func (f fakeSchema) Encode(interface{}) ([]byte, error) { return nil, nil }
+func (f fakeSchema) Decode([]byte) (interface{}, error) { return f.decoded, f.err }
+
+func TestDecoder_Decode(t *testing.T) {
+	raw := []byte{0, 0, 0, 0, 7, 'p', 'a', 'y', 'l', 'o', 'a', 'd'}
+
+	t.Run("resolves the schema by id and decodes the payload", func(t *testing.T) {
+		registry := &fakeSchemaRegistry{schemas: map[uint32]Schema{7: fakeSchema{decoded: "hello"}}}
+		decoder := NewDecoder(registry)
+
+		value, err := decoder.Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", value)
+	})
+
+	t.Run("propagates a registry lookup failure", func(t *testing.T) {
+		registry := &fakeSchemaRegistry{err: errors.New("boom")}
+		decoder := NewDecoder(registry)
+
+		_, err := decoder.Decode(raw)
+		assert.Error(t, err)
+	})
+
+	t.Run("propagates a schema decode failure", func(t *testing.T) {
+		registry := &fakeSchemaRegistry{schemas: map[uint32]Schema{7: fakeSchema{err: errors.New("bad payload")}}}
+		decoder := NewDecoder(registry)
+
+		_, err := decoder.Decode(raw)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects a malformed header before touching the registry", func(t *testing.T) {
+		registry := &fakeSchemaRegistry{err: errors.New("should not be called")}
+		decoder := NewDecoder(registry)
+
+		_, err := decoder.Decode([]byte{1, 2, 3})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "should not be called")
+	})
+}
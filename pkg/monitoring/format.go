@@ -0,0 +1,205 @@
+package monitoring
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/riferrei/srclient"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Format selects the serialization used to encode and decode a Schema's
+// payloads. The wire-format header (magic byte + 4-byte schema ID) is the
+// same across every format; only the payload encoding differs.
+type Format string
+
+const (
+	FormatAvro     Format = "avro"
+	FormatJSON     Format = "json"
+	FormatProtobuf Format = "protobuf"
+)
+
+// schemaType maps a Format onto the srclient.SchemaType CreateSchema
+// expects. The zero value defaults to avro, matching existing behaviour.
+func (f Format) schemaType() srclient.SchemaType {
+	switch f {
+	case FormatJSON:
+		return srclient.Json
+	case FormatProtobuf:
+		return srclient.Protobuf
+	default:
+		return srclient.Avro
+	}
+}
+
+// formatOf determines which Format a schema already fetched from the
+// registry was registered with, falling back to configured when the
+// backend doesn't report a type (older registries always assume avro).
+func formatOf(raw *srclient.Schema, configured Format) Format {
+	schemaType := raw.SchemaType()
+	if schemaType == nil {
+		return configured
+	}
+	switch *schemaType {
+	case srclient.Json:
+		return FormatJSON
+	case srclient.Protobuf:
+		return FormatProtobuf
+	default:
+		return FormatAvro
+	}
+}
+
+// wrapSchemaFor builds the Schema implementation matching format around a
+// schema fetched from, or just created in, the registry. The JSON Schema
+// and protobuf descriptor compilation this requires is memoized - by
+// srclient.Schema itself for JSON, and by s.cache for protobuf - so a
+// decoder resolving the same schema ID repeatedly on the hot path doesn't
+// recompile it on every message.
+func (s *schemaRegistry) wrapSchemaFor(format Format, raw *srclient.Schema) (Schema, error) {
+	switch format {
+	case FormatJSON:
+		compiled := raw.JsonSchema()
+		if compiled == nil {
+			return nil, fmt.Errorf("failed to compile json schema for subject")
+		}
+		return jsonWrapSchema{raw, compiled}, nil
+	case FormatProtobuf:
+		id := uint32(raw.ID())
+		descriptor, ok := s.cache.getProtobufDescriptor(id)
+		if !ok {
+			var err error
+			descriptor, err = parseProtobufSchema(raw.Schema())
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse protobuf schema: %w", err)
+			}
+			s.cache.setProtobufDescriptor(id, descriptor)
+		}
+		return protobufWrapSchema{raw, descriptor}, nil
+	default:
+		return wrapSchema{raw}, nil
+	}
+}
+
+func wireFormatWrap(schemaID uint32, payload []byte) []byte {
+	schemaIDBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(schemaIDBytes, schemaID)
+
+	// Magic 0 byte + 4 bytes of schema ID + the data bytes
+	bytes := []byte{wireFormatMagicByte}
+	bytes = append(bytes, schemaIDBytes...)
+	return append(bytes, payload...)
+}
+
+// Avro, via goavro (existing behaviour).
+
+type wrapSchema struct {
+	*srclient.Schema
+}
+
+func (w wrapSchema) Encode(value interface{}) ([]byte, error) {
+	payload, err := w.Schema.Codec().BinaryFromNative(nil, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value in avro: %w", err)
+	}
+	return wireFormatWrap(uint32(w.Schema.ID()), payload), nil
+}
+
+func (w wrapSchema) Decode(buf []byte) (interface{}, error) {
+	value, _, err := w.Schema.Codec().NativeFromBinary(buf)
+	return value, err
+}
+
+// JSON Schema, validated with santhosh-tekuri/jsonschema and encoded with
+// the standard library.
+
+type jsonWrapSchema struct {
+	*srclient.Schema
+	validator *jsonschema.Schema
+}
+
+func (w jsonWrapSchema) Encode(value interface{}) ([]byte, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value as json: %w", err)
+	}
+	var untyped interface{}
+	if err := json.Unmarshal(payload, &untyped); err != nil {
+		return nil, fmt.Errorf("failed to re-read encoded json for validation: %w", err)
+	}
+	if err := w.validator.Validate(untyped); err != nil {
+		return nil, fmt.Errorf("value does not match json schema: %w", err)
+	}
+	return wireFormatWrap(uint32(w.Schema.ID()), payload), nil
+}
+
+func (w jsonWrapSchema) Decode(buf []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(buf, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode json payload: %w", err)
+	}
+	if err := w.validator.Validate(value); err != nil {
+		return nil, fmt.Errorf("decoded value does not match json schema: %w", err)
+	}
+	return value, nil
+}
+
+// Protobuf, parsed from the .proto source the registry stores and encoded
+// via a dynamic message so the monitor doesn't need generated Go types for
+// every feed's wire schema.
+
+type protobufWrapSchema struct {
+	*srclient.Schema
+	descriptor *desc.MessageDescriptor
+}
+
+func (w protobufWrapSchema) Encode(value interface{}) ([]byte, error) {
+	asJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value for protobuf encoding: %w", err)
+	}
+	msg := dynamic.NewMessage(w.descriptor)
+	if err := msg.UnmarshalJSON(asJSON); err != nil {
+		return nil, fmt.Errorf("failed to build protobuf message: %w", err)
+	}
+	payload, err := msg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value as protobuf: %w", err)
+	}
+	return wireFormatWrap(uint32(w.Schema.ID()), payload), nil
+}
+
+func (w protobufWrapSchema) Decode(buf []byte) (interface{}, error) {
+	msg := dynamic.NewMessage(w.descriptor)
+	if err := msg.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf payload: %w", err)
+	}
+	asJSON, err := msg.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal decoded protobuf message: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(asJSON, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf payload as json: %w", err)
+	}
+	return value, nil
+}
+
+func parseProtobufSchema(source string) (*desc.MessageDescriptor, error) {
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"schema.proto": source}),
+	}
+	files, err := parser.ParseFiles("schema.proto")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protobuf schema source: %w", err)
+	}
+	messages := files[0].GetMessageTypes()
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("protobuf schema defines no message types")
+	}
+	return messages[0], nil
+}
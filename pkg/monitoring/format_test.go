@@ -0,0 +1,26 @@
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat_SchemaType(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   srclient.SchemaType
+	}{
+		{FormatAvro, srclient.Avro},
+		{FormatJSON, srclient.Json},
+		{FormatProtobuf, srclient.Protobuf},
+		{Format(""), srclient.Avro},
+		{Format("unknown"), srclient.Avro},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.schemaType())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/riferrei/srclient"
+)
+
+// maxSchemaCacheSize bounds the number of entries held in each cache so a
+// long-running process can't grow without bound, e.g. if a decoder is
+// exposed to an unbounded number of distinct schema IDs.
+const maxSchemaCacheSize = 1000
+
+// SchemaCacheEntry pairs a schema fetched from the registry with the time
+// it was fetched, so it can be expired once it's older than the configured
+// TTL.
+type SchemaCacheEntry struct {
+	Schema    *srclient.Schema
+	FetchedAt time.Time
+}
+
+func (e SchemaCacheEntry) expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && now.Sub(e.FetchedAt) > ttl
+}
+
+// schemaCache caches schemas by subject (the latest version seen for that
+// subject) and by numeric schema ID (for resolving the schema a given
+// message was written with). Entries older than ttl are treated as misses.
+// A ttl of zero disables expiry entirely but the cache is still used to
+// bound round-trips to the registry.
+type schemaCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+
+	bySubject    map[string]SchemaCacheEntry
+	subjectOrder []string
+	byID         map[uint32]SchemaCacheEntry
+	idOrder      []uint32
+
+	// protobufDescriptors caches the parsed message descriptor for a
+	// protobuf-format schema ID, so a decoder doesn't reparse the .proto
+	// source on every message.
+	protobufDescriptors     map[uint32]*desc.MessageDescriptor
+	protobufDescriptorOrder []uint32
+}
+
+func newSchemaCache(ttl time.Duration) *schemaCache {
+	return &schemaCache{
+		ttl:                 ttl,
+		bySubject:           make(map[string]SchemaCacheEntry),
+		byID:                make(map[uint32]SchemaCacheEntry),
+		protobufDescriptors: make(map[uint32]*desc.MessageDescriptor),
+	}
+}
+
+func (c *schemaCache) getBySubject(subject string) (*srclient.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.bySubject[subject]
+	if !ok || entry.expired(c.ttl, time.Now()) {
+		return nil, false
+	}
+	return entry.Schema, true
+}
+
+func (c *schemaCache) setBySubject(subject string, schema *srclient.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.bySubject[subject]; !ok {
+		if len(c.subjectOrder) >= maxSchemaCacheSize {
+			oldest := c.subjectOrder[0]
+			c.subjectOrder = c.subjectOrder[1:]
+			delete(c.bySubject, oldest)
+		}
+		c.subjectOrder = append(c.subjectOrder, subject)
+	}
+	c.bySubject[subject] = SchemaCacheEntry{Schema: schema, FetchedAt: time.Now()}
+}
+
+func (c *schemaCache) getByID(id uint32) (*srclient.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byID[id]
+	if !ok || entry.expired(c.ttl, time.Now()) {
+		return nil, false
+	}
+	return entry.Schema, true
+}
+
+func (c *schemaCache) setByID(id uint32, schema *srclient.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.byID[id]; !ok {
+		if len(c.idOrder) >= maxSchemaCacheSize {
+			oldest := c.idOrder[0]
+			c.idOrder = c.idOrder[1:]
+			delete(c.byID, oldest)
+		}
+		c.idOrder = append(c.idOrder, id)
+	}
+	c.byID[id] = SchemaCacheEntry{Schema: schema, FetchedAt: time.Now()}
+}
+
+// getProtobufDescriptor returns the previously-parsed message descriptor
+// for a protobuf-format schema ID, if any. A schema ID's descriptor never
+// changes once registered, so unlike the other entries this isn't subject
+// to ttl expiry - it's still bounded by maxSchemaCacheSize with the same
+// FIFO eviction, though, so a long-running decoder can't grow it forever.
+func (c *schemaCache) getProtobufDescriptor(id uint32) (*desc.MessageDescriptor, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	descriptor, ok := c.protobufDescriptors[id]
+	return descriptor, ok
+}
+
+func (c *schemaCache) setProtobufDescriptor(id uint32, descriptor *desc.MessageDescriptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.protobufDescriptors[id]; !ok {
+		if len(c.protobufDescriptorOrder) >= maxSchemaCacheSize {
+			oldest := c.protobufDescriptorOrder[0]
+			c.protobufDescriptorOrder = c.protobufDescriptorOrder[1:]
+			delete(c.protobufDescriptors, oldest)
+		}
+		c.protobufDescriptorOrder = append(c.protobufDescriptorOrder, id)
+	}
+	c.protobufDescriptors[id] = descriptor
+}
+
+// Flush clears every cached entry. Intended for use in tests that need to
+// force a fresh round-trip to the registry.
+func (c *schemaCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bySubject = make(map[string]SchemaCacheEntry)
+	c.subjectOrder = nil
+	c.byID = make(map[uint32]SchemaCacheEntry)
+	c.idOrder = nil
+	c.protobufDescriptors = make(map[uint32]*desc.MessageDescriptor)
+	c.protobufDescriptorOrder = nil
+}
@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCache_SubjectRoundTrip(t *testing.T) {
+	cache := newSchemaCache(0)
+	schema := new(srclient.Schema)
+
+	_, ok := cache.getBySubject("subject-a")
+	require.False(t, ok)
+
+	cache.setBySubject("subject-a", schema)
+	got, ok := cache.getBySubject("subject-a")
+	require.True(t, ok)
+	assert.Same(t, schema, got)
+}
+
+func TestSchemaCache_IDRoundTrip(t *testing.T) {
+	cache := newSchemaCache(0)
+	schema := new(srclient.Schema)
+
+	_, ok := cache.getByID(7)
+	require.False(t, ok)
+
+	cache.setByID(7, schema)
+	got, ok := cache.getByID(7)
+	require.True(t, ok)
+	assert.Same(t, schema, got)
+}
+
+func TestSchemaCache_EntriesExpireAfterTTL(t *testing.T) {
+	cache := newSchemaCache(10 * time.Millisecond)
+	cache.setBySubject("subject-a", new(srclient.Schema))
+
+	_, ok := cache.getBySubject("subject-a")
+	require.True(t, ok, "entry should be fresh immediately after being set")
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = cache.getBySubject("subject-a")
+	assert.False(t, ok, "entry should have expired once older than the ttl")
+}
+
+func TestSchemaCache_ZeroTTLNeverExpires(t *testing.T) {
+	cache := newSchemaCache(0)
+	cache.setBySubject("subject-a", new(srclient.Schema))
+
+	time.Sleep(10 * time.Millisecond)
+	_, ok := cache.getBySubject("subject-a")
+	assert.True(t, ok, "a zero ttl should disable expiry")
+}
+
+func TestSchemaCache_EvictsOldestIDEntryWhenFull(t *testing.T) {
+	cache := newSchemaCache(0)
+	for i := uint32(0); i < maxSchemaCacheSize; i++ {
+		cache.setByID(i, new(srclient.Schema))
+	}
+	_, ok := cache.getByID(0)
+	require.True(t, ok, "cache should not evict before reaching its size cap")
+
+	cache.setByID(maxSchemaCacheSize, new(srclient.Schema))
+
+	_, ok = cache.getByID(0)
+	assert.False(t, ok, "oldest entry should have been evicted once the cache is full")
+	_, ok = cache.getByID(maxSchemaCacheSize)
+	assert.True(t, ok)
+}
+
+func TestSchemaCache_EvictsOldestProtobufDescriptorWhenFull(t *testing.T) {
+	cache := newSchemaCache(0)
+	for i := uint32(0); i < maxSchemaCacheSize; i++ {
+		cache.setProtobufDescriptor(i, nil)
+	}
+	_, ok := cache.getProtobufDescriptor(0)
+	require.True(t, ok, "cache should not evict before reaching its size cap")
+
+	cache.setProtobufDescriptor(maxSchemaCacheSize, nil)
+
+	_, ok = cache.getProtobufDescriptor(0)
+	assert.False(t, ok, "oldest descriptor should have been evicted once the cache is full")
+	_, ok = cache.getProtobufDescriptor(maxSchemaCacheSize)
+	assert.True(t, ok)
+}
+
+func TestSchemaCache_Flush(t *testing.T) {
+	cache := newSchemaCache(0)
+	cache.setBySubject("subject-a", new(srclient.Schema))
+	cache.setByID(1, new(srclient.Schema))
+	cache.setProtobufDescriptor(1, nil)
+
+	cache.Flush()
+
+	_, ok := cache.getBySubject("subject-a")
+	assert.False(t, ok)
+	_, ok = cache.getByID(1)
+	assert.False(t, ok)
+	_, ok = cache.getProtobufDescriptor(1)
+	assert.False(t, ok)
+}
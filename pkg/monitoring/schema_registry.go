@@ -1,7 +1,6 @@
 package monitoring
 
 import (
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -17,11 +16,22 @@ type SchemaRegistry interface {
 	// 2. if a schema with the given subject already exists but the spec is different, it will update it and bump the version.
 	// 3. if the schema exists and the spec is the same, it will not do anything.
 	EnsureSchema(subject, spec string) (Schema, error)
+	// GetSchemaByID resolves the schema a message was written with from the
+	// numeric ID embedded in its wire-format header. This is what lets a
+	// decoder handle a topic containing records written against several
+	// schema versions, rather than assuming the latest one.
+	GetSchemaByID(id uint32) (Schema, error)
+	// Flush clears the schema cache, forcing the next call to round-trip
+	// to the registry. Intended for use in tests.
+	Flush()
 }
 
 type schemaRegistry struct {
-	backend *srclient.SchemaRegistryClient
-	log     logger.Logger
+	backend       *srclient.SchemaRegistryClient
+	cache         *schemaCache
+	format        Format
+	compatibility CompatibilityLevel
+	log           logger.Logger
 }
 
 func NewSchemaRegistry(cfg SchemaRegistryConfig, log logger.Logger) SchemaRegistry {
@@ -29,21 +39,33 @@ func NewSchemaRegistry(cfg SchemaRegistryConfig, log logger.Logger) SchemaRegist
 	if cfg.Username != "" && cfg.Password != "" {
 		backend.SetCredentials(cfg.Username, cfg.Password)
 	}
-	return &schemaRegistry{backend, log}
+	return &schemaRegistry{backend, newSchemaCache(cfg.CacheTTL), cfg.Format, cfg.Compatibility, log}
 }
 
 func (s *schemaRegistry) EnsureSchema(subject, spec string) (Schema, error) {
+	if cached, ok := s.cache.getBySubject(subject); ok {
+		isEqualSchemas, err := isEqualJSON(cached.Schema(), spec)
+		if err == nil && isEqualSchemas {
+			return s.wrapSchemaFor(s.format, cached)
+		}
+	}
+
 	registeredSchema, err := s.backend.GetLatestSchema(subject)
 	if err != nil && !isNotFoundErr(err) {
 		return nil, fmt.Errorf("failed to read schema for subject '%s': %w", subject, err)
 	}
 	if err != nil && isNotFoundErr(err) {
 		s.log.Infof("creating new schema for subject '%s'\n", subject)
-		newSchema, err := s.backend.CreateSchema(subject, spec, srclient.Avro)
+		newSchema, err := s.backend.CreateSchema(subject, spec, s.format.schemaType())
 		if err != nil {
 			return nil, fmt.Errorf("unable to create new schema with subject '%s': %w", subject, err)
 		}
-		return wrapSchema{newSchema}, nil
+		if err := s.ensureCompatibility(subject); err != nil {
+			return nil, err
+		}
+		s.cache.setBySubject(subject, newSchema)
+		s.cache.setByID(uint32(newSchema.ID()), newSchema)
+		return s.wrapSchemaFor(s.format, newSchema)
 	}
 	isEqualSchemas, errInIsEqualJSON := isEqualJSON(registeredSchema.Schema(), spec)
 	if errInIsEqualJSON != nil {
@@ -51,14 +73,36 @@ func (s *schemaRegistry) EnsureSchema(subject, spec string) (Schema, error) {
 	}
 	if isEqualSchemas {
 		s.log.Infof("using existing schema for subject '%s'\n", subject)
-		return wrapSchema{registeredSchema}, nil
+		s.cache.setBySubject(subject, registeredSchema)
+		s.cache.setByID(uint32(registeredSchema.ID()), registeredSchema)
+		return s.wrapSchemaFor(s.format, registeredSchema)
 	}
 	s.log.Infof("updating schema for subject '%s'\n", subject)
-	newSchema, err := s.backend.CreateSchema(subject, spec, srclient.Avro)
+	newSchema, err := s.backend.CreateSchema(subject, spec, s.format.schemaType())
 	if err != nil {
 		return nil, fmt.Errorf("unable to update schema with subject '%s': %w", subject, err)
 	}
-	return wrapSchema{newSchema}, nil
+	if err := s.ensureCompatibility(subject); err != nil {
+		return nil, err
+	}
+	s.cache.setBySubject(subject, newSchema)
+	s.cache.setByID(uint32(newSchema.ID()), newSchema)
+	return s.wrapSchemaFor(s.format, newSchema)
+}
+
+// ensureCompatibility applies the configured compatibility level to
+// subject. It's only called on the branches that write a new schema
+// version (create or update) - not on the cache-hit or already-registered
+// fast paths - so EnsureSchema doesn't pay a registry round-trip when
+// nothing about the subject actually changed.
+func (s *schemaRegistry) ensureCompatibility(subject string) error {
+	if s.compatibility == "" {
+		return nil
+	}
+	if _, err := s.backend.ChangeSubjectCompatibilityLevel(subject, srclient.CompatibilityLevel(s.compatibility)); err != nil {
+		return fmt.Errorf("unable to set compatibility level '%s' for subject '%s': %w", s.compatibility, subject, err)
+	}
+	return nil
 }
 
 type Schema interface {
@@ -66,29 +110,20 @@ type Schema interface {
 	Decode([]byte) (interface{}, error)
 }
 
-type wrapSchema struct {
-	*srclient.Schema
-}
-
-func (w wrapSchema) Encode(value interface{}) ([]byte, error) {
-	payload, err := w.Schema.Codec().BinaryFromNative(nil, value)
+func (s *schemaRegistry) GetSchemaByID(id uint32) (Schema, error) {
+	if cached, ok := s.cache.getByID(id); ok {
+		return s.wrapSchemaFor(formatOf(cached, s.format), cached)
+	}
+	schema, err := s.backend.GetSchema(int(id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode value in avro: %w", err)
+		return nil, fmt.Errorf("failed to fetch schema with id '%d': %w", id, err)
 	}
-	schemaIDBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(schemaIDBytes, uint32(w.Schema.ID()))
-
-	// Magic 0 byte + 4 bytes of schema ID + the data bytes
-	bytes := []byte{0}
-	bytes = append(bytes, schemaIDBytes...)
-	bytes = append(bytes, payload...)
-	return bytes, nil
+	s.cache.setByID(id, schema)
+	return s.wrapSchemaFor(formatOf(schema, s.format), schema)
 }
 
-func (w wrapSchema) Decode(buf []byte) (interface{}, error) {
-	// TODO add the decode for tests later
-	value, _, err := w.Schema.Codec().NativeFromBinary(buf)
-	return value, err
+func (s *schemaRegistry) Flush() {
+	s.cache.Flush()
 }
 
 // Helpers
@@ -0,0 +1,279 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeSchemaRegistryServer is a minimal in-memory implementation of the
+// Confluent Schema Registry REST API - just enough of it for a real
+// srclient client to exercise schemaRegistry against, without a live
+// registry.
+type fakeSchemaRegistryServer struct {
+	mu     sync.Mutex
+	nextID int
+
+	bySubject map[string]*registeredSchemaVersion
+	byID      map[int]string
+
+	createCalls        int
+	latestCalls        int
+	getByIDCalls       int
+	compatibilityCalls map[string]int
+}
+
+type registeredSchemaVersion struct {
+	id      int
+	version int
+	schema  string
+}
+
+func newFakeSchemaRegistryServer() *fakeSchemaRegistryServer {
+	return &fakeSchemaRegistryServer{
+		nextID:             1,
+		bySubject:          make(map[string]*registeredSchemaVersion),
+		byID:               make(map[int]string),
+		compatibilityCalls: make(map[string]int),
+	}
+}
+
+func (f *fakeSchemaRegistryServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/subjects/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/subjects/")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/versions/latest"):
+			f.getLatest(w, strings.TrimSuffix(path, "/versions/latest"))
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/versions"):
+			f.create(w, r, strings.TrimSuffix(path, "/versions"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/schemas/ids/", func(w http.ResponseWriter, r *http.Request) {
+		f.getByID(w, strings.TrimPrefix(r.URL.Path, "/schemas/ids/"))
+	})
+	mux.HandleFunc("/config/", func(w http.ResponseWriter, r *http.Request) {
+		f.setCompatibility(w, r, strings.TrimPrefix(r.URL.Path, "/config/"))
+	})
+	return mux
+}
+
+func (f *fakeSchemaRegistryServer) getLatest(w http.ResponseWriter, subject string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latestCalls++
+
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	entry, ok := f.bySubject[subject]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error_code": 40401,
+			"message":    "Subject not found.",
+		})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"subject": subject,
+		"id":      entry.id,
+		"version": entry.version,
+		"schema":  entry.schema,
+	})
+}
+
+func (f *fakeSchemaRegistryServer) create(w http.ResponseWriter, r *http.Request, subject string) {
+	var body struct {
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createCalls++
+
+	id := f.nextID
+	f.nextID++
+	version := 1
+	if existing, ok := f.bySubject[subject]; ok {
+		version = existing.version + 1
+	}
+	f.bySubject[subject] = &registeredSchemaVersion{id: id, version: version, schema: body.Schema}
+	f.byID[id] = body.Schema
+
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": id})
+}
+
+func (f *fakeSchemaRegistryServer) getByID(w http.ResponseWriter, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getByIDCalls++
+
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	schema, ok := f.byID[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error_code": 40403,
+			"message":    "Schema not found.",
+		})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"schema": schema})
+}
+
+func (f *fakeSchemaRegistryServer) setCompatibility(w http.ResponseWriter, r *http.Request, subject string) {
+	var body struct {
+		Compatibility string `json:"compatibility"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.compatibilityCalls[subject]++
+
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"compatibility": body.Compatibility})
+}
+
+// testLoggerConfig mirrors the logger.Config cmd/monitoring/main.go wires
+// up (that one is unexported there), except that it logs at error level.
+type testLoggerConfig struct{}
+
+var _ logger.Config = testLoggerConfig{}
+
+func (l testLoggerConfig) RootDir() string {
+	return "" // Not logging to disk.
+}
+
+func (l testLoggerConfig) JSONConsole() bool {
+	return false
+}
+
+func (l testLoggerConfig) LogToDisk() bool {
+	return false
+}
+
+func (l testLoggerConfig) LogLevel() zapcore.Level {
+	return zapcore.ErrorLevel // keep test output quiet
+}
+
+func (l testLoggerConfig) LogUnixTimestamps() bool {
+	return false
+}
+
+func testLogger() logger.Logger {
+	return logger.NewLogger(testLoggerConfig{})
+}
+
+func TestSchemaRegistry_EnsureSchema_CreateAndUpdate(t *testing.T) {
+	fake := newFakeSchemaRegistryServer()
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	registry := NewSchemaRegistry(SchemaRegistryConfig{
+		URL:           server.URL,
+		Compatibility: CompatibilityBackward,
+	}, testLogger())
+
+	spec := `{"type":"string"}`
+
+	_, err := registry.EnsureSchema("widget-value", spec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.createCalls)
+	assert.Equal(t, 1, fake.compatibilityCalls["widget-value"], "a newly-created subject should get its compatibility level set")
+
+	// The same spec again should be served from our own cache: no extra
+	// registry round-trips at all.
+	_, err = registry.EnsureSchema("widget-value", spec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.createCalls, "an unchanged spec should not create a new version")
+	assert.Equal(t, 1, fake.compatibilityCalls["widget-value"], "a cache hit should not re-apply compatibility")
+
+	// A genuinely different spec should create a new version and re-apply
+	// compatibility on that write.
+	_, err = registry.EnsureSchema("widget-value", `{"type":"long"}`)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.createCalls)
+	assert.Equal(t, 2, fake.compatibilityCalls["widget-value"])
+}
+
+func TestSchemaRegistry_EnsureSchema_ExistingEqualSchemaSkipsCompatibility(t *testing.T) {
+	fake := newFakeSchemaRegistryServer()
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	spec := `{"type":"string"}`
+	registry := NewSchemaRegistry(SchemaRegistryConfig{
+		URL:           server.URL,
+		Compatibility: CompatibilityBackward,
+	}, testLogger())
+
+	_, err := registry.EnsureSchema("widget-value", spec)
+	require.NoError(t, err)
+
+	// Force a cache miss so the next call has to round-trip to the
+	// registry, but the registry already agrees with the local spec.
+	registry.Flush()
+	_, err = registry.EnsureSchema("widget-value", spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fake.createCalls, "an already-registered equal schema should not create a new version")
+	assert.Equal(t, 1, fake.compatibilityCalls["widget-value"], "the existing-equal-schema path should not re-apply compatibility")
+}
+
+func TestSchemaRegistry_GetSchemaByID(t *testing.T) {
+	fake := newFakeSchemaRegistryServer()
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	registry := NewSchemaRegistry(SchemaRegistryConfig{URL: server.URL}, testLogger())
+
+	_, err := registry.EnsureSchema("widget-value", `{"type":"string"}`)
+	require.NoError(t, err)
+
+	// Force a cache miss so GetSchemaByID has to round-trip once.
+	registry.Flush()
+
+	schema, err := registry.GetSchemaByID(1)
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+	assert.Equal(t, 1, fake.getByIDCalls)
+
+	// A second lookup of the same ID should be served from cache.
+	_, err = registry.GetSchemaByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.getByIDCalls, "a cached schema id should not round-trip to the registry again")
+}
+
+func TestSchemaRegistry_GetSchemaByID_UnknownIDFails(t *testing.T) {
+	fake := newFakeSchemaRegistryServer()
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	registry := NewSchemaRegistry(SchemaRegistryConfig{URL: server.URL}, testLogger())
+
+	_, err := registry.GetSchemaByID(999)
+	assert.Error(t, err)
+}